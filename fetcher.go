@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	latestRepoCnt    = 5
+	enableSortByName = true
+)
+
+// Fetcher fetches the most recently pushed repositories from a git hosting
+// backend and maps them into the README's template model. GitHub, GitLab and
+// Gitea/Gogs each get their own implementation so the README-updater can run
+// against self-hosted forges as well as github.com.
+type Fetcher interface {
+	FetchLatestProjects() ([]latestProjectEntry, error)
+}
+
+type latestProjectEntry struct {
+	// repo info
+	RepoName string
+	RepoUrl  string
+	RepoLang string
+
+	// commit info
+	BranchName      string
+	BranchUrl       string
+	CommitID        string
+	CommitUrl       string
+	CommitAuthorID  string
+	CommitAuthorUrl string
+
+	// time info
+	Time string
+
+	// local git-module stats, populated only when --git-stats is set
+	LinesAdded   int
+	LinesDeleted int
+	FilesChanged int
+	DiffSummary  string
+}
+
+func fmtDuration(d time.Duration) string {
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	return fmt.Sprintf("%d hours %d minutes", h, m)
+}
+
+// newFetcher builds the Fetcher named by source, reading its backend-specific
+// configuration (base URL, token, ...) from the environment.
+func newFetcher(source string) (Fetcher, error) {
+	switch source {
+	case "", "github":
+		return NewGitHubFetcher()
+	case "gitlab":
+		return NewGitLabFetcher(), nil
+	case "gitea", "gogs":
+		return NewGiteaFetcher(), nil
+	default:
+		return nil, fmt.Errorf("unknown --source %q, want github, gitlab, or gitea", source)
+	}
+}
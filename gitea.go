@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// GiteaFetcher fetches the viewer's most recently pushed repositories via the
+// Gitea/Gogs REST API (https://docs.gitea.io/en-us/api-usage/), which the two
+// projects share.
+type GiteaFetcher struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+// NewGiteaFetcher builds a GiteaFetcher reading its base URL and token from
+// GITEA_BASE_URL and GITEA_TOKEN.
+func NewGiteaFetcher() *GiteaFetcher {
+	return &GiteaFetcher{
+		BaseURL: os.Getenv("GITEA_BASE_URL"),
+		Token:   os.Getenv("GITEA_TOKEN"),
+		Client:  http.DefaultClient,
+	}
+}
+
+type giteaRepo struct {
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	HTMLURL       string    `json:"html_url"`
+	Language      string    `json:"language"`
+	DefaultBranch string    `json:"default_branch"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type giteaCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Author  *struct {
+		Login   string `json:"login"`
+		HTMLURL string `json:"html_url"`
+	} `json:"author"`
+	Commit struct {
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+func (f *GiteaFetcher) get(path string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, f.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if f.Token != "" {
+		req.Header.Set("Authorization", "token "+f.Token)
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// FetchLatestProjects walks pages of repos (newest-updated first) until it
+// has latestRepoCnt valid entries or runs out of pages. Paging (rather than
+// trimming a single page up front) keeps repos with an empty default branch
+// or no commits from shrinking the final result below latestRepoCnt when
+// more valid repos are sitting on the next page.
+func (f *GiteaFetcher) FetchLatestProjects() ([]latestProjectEntry, error) {
+	var result []latestProjectEntry
+	baseTime := time.Now()
+
+	for page := 1; len(result) < latestRepoCnt; page++ {
+		var repos []giteaRepo
+		query := url.Values{
+			"limit": {fmt.Sprintf("%d", latestRepoCnt)},
+			"page":  {fmt.Sprintf("%d", page)},
+			"sort":  {"updated"},
+			"order": {"desc"},
+		}
+		if err := f.get("/api/v1/user/repos?"+query.Encode(), &repos); err != nil {
+			return nil, err
+		}
+		if len(repos) == 0 {
+			break
+		}
+
+		for _, repo := range repos {
+			if len(result) >= latestRepoCnt {
+				break
+			}
+			entry, err := f.repoEntry(repo, baseTime)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil {
+				continue
+			}
+			result = append(result, *entry)
+		}
+
+		if len(repos) < latestRepoCnt {
+			break // last page
+		}
+	}
+
+	return result, nil
+}
+
+// repoEntry fetches repo's latest commit and renders it as a
+// latestProjectEntry, or returns (nil, nil) if the repo has no default
+// branch or no commits to report on.
+func (f *GiteaFetcher) repoEntry(repo giteaRepo, baseTime time.Time) (*latestProjectEntry, error) {
+	if repo.DefaultBranch == "" {
+		return nil, nil
+	}
+	var commits []giteaCommit
+	commitsPath := fmt.Sprintf("/api/v1/repos/%s/commits?sha=%s&limit=1",
+		repo.FullName, url.QueryEscape(repo.DefaultBranch))
+	if err := f.get(commitsPath, &commits); err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	commit := commits[0]
+
+	entry := latestProjectEntry{
+		RepoName:   repo.Name,
+		RepoUrl:    repo.HTMLURL,
+		RepoLang:   repo.Language,
+		BranchName: repo.DefaultBranch,
+		BranchUrl:  repo.HTMLURL + "/src/branch/" + repo.DefaultBranch,
+		CommitID:   commit.SHA[:min(len(commit.SHA), 7)],
+		CommitUrl:  commit.HTMLURL,
+	}
+	if commit.Author != nil {
+		entry.CommitAuthorID = commit.Author.Login
+		entry.CommitAuthorUrl = commit.Author.HTMLURL
+	} else {
+		entry.CommitAuthorID = commit.Commit.Author.Name
+	}
+	entry.Time = fmtDuration(baseTime.Sub(repo.UpdatedAt).Round(time.Minute))
+	if entry.RepoLang == "" {
+		entry.RepoLang = "unknown"
+	}
+	return &entry, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
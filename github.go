@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+GraphQL:
+
+{
+  viewer {
+	login
+    repositories(first: 100, privacy: PUBLIC, orderBy: {field: PUSHED_AT, direction: DESC}) {
+      nodes {
+        name
+        url
+        primaryLanguage {
+          name
+        }
+        pushedAt
+        isFork
+        refs(refPrefix: "refs/heads/", orderBy: {field: TAG_COMMIT_DATE, direction: DESC}, first: 1) {
+          edges {
+            node {
+              name
+              target {
+                ... on Commit {
+                  history(first: 1) {
+                    edges {
+                      node {
+                        commitUrl
+                        author {
+                          user {
+                            login
+                            url
+                          }
+                        }
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+
+The same shape is re-queried for `organization(login: $login) { repositories(...) }` and
+`user(login: $login) { repositories(...) }` with a cursor-paginated pageInfo block, so that
+repos from other accounts can be aggregated alongside the viewer's own.
+*/
+
+const (
+	reposPageSize = 100
+
+	// sourcesEnvVar holds a comma-separated list of "kind:login" pairs (kind is
+	// "org" or "user") naming additional accounts to aggregate repos from,
+	// e.g. "org:golang,user:octocat". The viewer is always included.
+	sourcesEnvVar = "GITHUB_EXTRA_SOURCES"
+)
+
+// GitHubFetcher fetches recently pushed repos from github.com (or a GitHub
+// Enterprise instance) across the viewer plus any configured extra Sources.
+type GitHubFetcher struct {
+	Client  *githubv4.Client
+	Sources []Source
+}
+
+// NewGitHubFetcher builds a GitHubFetcher authenticated with GITHUB_TOKEN and
+// configured to aggregate the viewer's repos plus any extra Sources named by
+// the GITHUB_EXTRA_SOURCES environment variable.
+func NewGitHubFetcher() (*GitHubFetcher, error) {
+	sources, err := extraSources()
+	if err != nil {
+		return nil, err
+	}
+	return &GitHubFetcher{
+		Client:  newGitHubClient(),
+		Sources: append([]Source{{Kind: "viewer"}}, sources...),
+	}, nil
+}
+
+// newGitHubClient builds a githubv4 client authenticated with GITHUB_TOKEN,
+// wrapped with exponential-backoff retries so paged queries ride out
+// transient 5xx errors and secondary rate limits instead of failing outright.
+func newGitHubClient() *githubv4.Client {
+	src := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
+	)
+	httpClient := oauth2.NewClient(context.Background(), src)
+	httpClient.Transport = &retryingTransport{base: httpClient.Transport}
+	return githubv4.NewClient(httpClient)
+}
+
+// Source identifies a GitHub account to pull repositories from.
+type Source struct {
+	Login string
+	Kind  string // "viewer", "org", or "user"
+}
+
+func extraSources() ([]Source, error) {
+	raw := os.Getenv(sourcesEnvVar)
+	if raw == "" {
+		return nil, nil
+	}
+	var sources []Source
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s: invalid source %q, want kind:login", sourcesEnvVar, pair)
+		}
+		sources = append(sources, Source{Kind: parts[0], Login: parts[1]})
+	}
+	return sources, nil
+}
+
+// repoNode mirrors the repository shape shared by the viewer, organization
+// and user GraphQL queries below.
+type repoNode struct {
+	Name            string
+	Url             string
+	PrimaryLanguage struct {
+		Name string
+	}
+	PushedAt time.Time
+	IsFork   bool
+	Refs     struct {
+		Edges []struct {
+			Node struct {
+				Name   string
+				Target struct {
+					Commit struct {
+						History struct {
+							Edges []struct {
+								Node struct {
+									CommitUrl      string
+									AbbreviatedOid string
+									Author         struct {
+										User struct {
+											Login string
+											Url   string
+										}
+									}
+								}
+							}
+						} `graphql:"history(first: 1)"`
+					} `graphql:"... on Commit"`
+				}
+			}
+		}
+	} `graphql:"refs(refPrefix: \"refs/heads/\", orderBy: {field: TAG_COMMIT_DATE, direction: DESC}, first: 1)"`
+}
+
+type repoPageInfo struct {
+	EndCursor   githubv4.String
+	HasNextPage bool
+}
+
+// fetchSourceRepos walks every page of public repositories belonging to
+// source, returning all of them along with the viewer's own login (only
+// populated when source.Kind is "viewer").
+func (f *GitHubFetcher) fetchSourceRepos(source Source) (repos []repoNode, viewerLogin string, err error) {
+	var cursor *githubv4.String
+	for {
+		variables := map[string]interface{}{
+			"pageSize": githubv4.Int(reposPageSize),
+			"cursor":   cursor,
+		}
+
+		var nodes []repoNode
+		var page repoPageInfo
+
+		switch source.Kind {
+		case "", "viewer":
+			var query struct {
+				Viewer struct {
+					Login        string
+					Repositories struct {
+						Nodes    []repoNode
+						PageInfo repoPageInfo
+					} `graphql:"repositories(first: $pageSize, after: $cursor, privacy: PUBLIC, orderBy: {field: PUSHED_AT, direction: DESC})"`
+				}
+			}
+			if err := f.Client.Query(context.Background(), &query, variables); err != nil {
+				return nil, "", err
+			}
+			viewerLogin = query.Viewer.Login
+			nodes, page = query.Viewer.Repositories.Nodes, query.Viewer.Repositories.PageInfo
+		case "org":
+			variables["login"] = githubv4.String(source.Login)
+			var query struct {
+				Organization struct {
+					Repositories struct {
+						Nodes    []repoNode
+						PageInfo repoPageInfo
+					} `graphql:"repositories(first: $pageSize, after: $cursor, privacy: PUBLIC, orderBy: {field: PUSHED_AT, direction: DESC})"`
+				} `graphql:"organization(login: $login)"`
+			}
+			if err := f.Client.Query(context.Background(), &query, variables); err != nil {
+				return nil, "", err
+			}
+			nodes, page = query.Organization.Repositories.Nodes, query.Organization.Repositories.PageInfo
+		case "user":
+			variables["login"] = githubv4.String(source.Login)
+			var query struct {
+				User struct {
+					Repositories struct {
+						Nodes    []repoNode
+						PageInfo repoPageInfo
+					} `graphql:"repositories(first: $pageSize, after: $cursor, privacy: PUBLIC, orderBy: {field: PUSHED_AT, direction: DESC})"`
+				} `graphql:"user(login: $login)"`
+			}
+			if err := f.Client.Query(context.Background(), &query, variables); err != nil {
+				return nil, "", err
+			}
+			nodes, page = query.User.Repositories.Nodes, query.User.Repositories.PageInfo
+		default:
+			return nil, "", fmt.Errorf("%s: unknown source kind %q", sourcesEnvVar, source.Kind)
+		}
+
+		repos = append(repos, nodes...)
+		if !page.HasNextPage {
+			return repos, viewerLogin, nil
+		}
+		cursor = &page.EndCursor
+	}
+}
+
+// filterReposWithHistory drops repos with no branches or no commit history,
+// which would otherwise panic on indexing into their empty Edges slices.
+func filterReposWithHistory(repos []repoNode) []repoNode {
+	var filtered []repoNode
+	for _, repo := range repos {
+		if len(repo.Refs.Edges) == 0 {
+			continue
+		}
+		if len(repo.Refs.Edges[0].Node.Target.Commit.History.Edges) == 0 {
+			continue
+		}
+		filtered = append(filtered, repo)
+	}
+	return filtered
+}
+
+func (f *GitHubFetcher) FetchLatestProjects() ([]latestProjectEntry, error) {
+	var allRepos []repoNode
+	var viewerLogin string
+	for _, source := range f.Sources {
+		repos, login, err := f.fetchSourceRepos(source)
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repos...)
+		if login != "" {
+			viewerLogin = login
+		}
+	}
+
+	// re-rank across every aggregated account before trimming
+	sort.SliceStable(allRepos, func(i, j int) bool {
+		return allRepos[i].PushedAt.After(allRepos[j].PushedAt)
+	})
+
+	// Repos with no branches (newly created, or with an empty default
+	// branch) or no commit history have nothing to report on; drop them
+	// before trimming so the cut doesn't throw away valid repos further
+	// down the ranked list.
+	allRepos = filterReposWithHistory(allRepos)
+	if len(allRepos) > latestRepoCnt {
+		allRepos = allRepos[:latestRepoCnt]
+	}
+
+	var result []latestProjectEntry
+	baseTime := time.Now()
+	for _, repo := range allRepos {
+		branch := repo.Refs.Edges[0].Node
+		commit := branch.Target.Commit.History.Edges[0].Node
+
+		entry := latestProjectEntry{
+			RepoName:        repo.Name,
+			RepoUrl:         repo.Url,
+			RepoLang:        repo.PrimaryLanguage.Name,
+			BranchName:      branch.Name,
+			BranchUrl:       repo.Url + "/tree/" + branch.Name,
+			CommitUrl:       commit.CommitUrl,
+			CommitID:        commit.AbbreviatedOid,
+			CommitAuthorID:  commit.Author.User.Login,
+			CommitAuthorUrl: commit.Author.User.Url,
+		}
+		durationTime := baseTime.Sub(repo.PushedAt).Round(time.Minute)
+		entry.Time = fmtDuration(durationTime)
+		if entry.RepoLang == "" {
+			entry.RepoLang = "unknown"
+		}
+		if entry.CommitAuthorID == "" {
+			// bot commits and commits from deleted accounts have no linked user
+			entry.CommitAuthorID = "unknown"
+		}
+		result = append(result, entry)
+	}
+	if enableSortByName {
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].CommitAuthorID == viewerLogin && result[j].CommitAuthorID != viewerLogin
+		})
+	}
+
+	return result, nil
+}
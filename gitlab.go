@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// GitLabFetcher fetches the viewer's most recently active GitLab projects via
+// GitLab's REST API (https://docs.gitlab.com/ee/api/projects.html and
+// .../commits.html), the same endpoints go-gitlab-client wraps.
+type GitLabFetcher struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+// NewGitLabFetcher builds a GitLabFetcher reading its base URL and token from
+// GITLAB_BASE_URL (default https://gitlab.com) and GITLAB_TOKEN.
+func NewGitLabFetcher() *GitLabFetcher {
+	baseURL := os.Getenv("GITLAB_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	return &GitLabFetcher{
+		BaseURL: baseURL,
+		Token:   os.Getenv("GITLAB_TOKEN"),
+		Client:  http.DefaultClient,
+	}
+}
+
+type gitlabProject struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	WebURL         string    `json:"web_url"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+	DefaultBranch  string    `json:"default_branch"`
+}
+
+type gitlabCommit struct {
+	ShortID    string `json:"short_id"`
+	WebURL     string `json:"web_url"`
+	AuthorName string `json:"author_name"`
+}
+
+func (f *GitLabFetcher) get(path string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, f.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if f.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", f.Token)
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// primaryLanguage returns the project's most-used language, or "" if the
+// languages endpoint fails or reports none.
+func (f *GitLabFetcher) primaryLanguage(projectID int) string {
+	var languages map[string]float64
+	if err := f.get(fmt.Sprintf("/api/v4/projects/%d/languages", projectID), &languages); err != nil {
+		return ""
+	}
+	var best string
+	var bestShare float64
+	for lang, share := range languages {
+		if share > bestShare {
+			best, bestShare = lang, share
+		}
+	}
+	return best
+}
+
+// FetchLatestProjects walks pages of projects (newest-activity first) until
+// it has latestRepoCnt valid entries or runs out of pages. Paging (rather
+// than trimming a single page up front) keeps projects with an empty
+// default branch or no commits from shrinking the final result below
+// latestRepoCnt when more valid projects are sitting on the next page.
+func (f *GitLabFetcher) FetchLatestProjects() ([]latestProjectEntry, error) {
+	var result []latestProjectEntry
+	baseTime := time.Now()
+
+	for page := 1; len(result) < latestRepoCnt; page++ {
+		var projects []gitlabProject
+		query := url.Values{
+			"membership": {"true"},
+			"order_by":   {"last_activity_at"},
+			"sort":       {"desc"},
+			"per_page":   {fmt.Sprintf("%d", latestRepoCnt)},
+			"page":       {fmt.Sprintf("%d", page)},
+		}
+		if err := f.get("/api/v4/projects?"+query.Encode(), &projects); err != nil {
+			return nil, err
+		}
+		if len(projects) == 0 {
+			break
+		}
+
+		for _, project := range projects {
+			if len(result) >= latestRepoCnt {
+				break
+			}
+			entry, err := f.projectEntry(project, baseTime)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil {
+				continue
+			}
+			result = append(result, *entry)
+		}
+
+		if len(projects) < latestRepoCnt {
+			break // last page
+		}
+	}
+
+	return result, nil
+}
+
+// projectEntry fetches project's latest commit and renders it as a
+// latestProjectEntry, or returns (nil, nil) if the project has no default
+// branch or no commits to report on.
+func (f *GitLabFetcher) projectEntry(project gitlabProject, baseTime time.Time) (*latestProjectEntry, error) {
+	if project.DefaultBranch == "" {
+		return nil, nil
+	}
+	var commits []gitlabCommit
+	commitsPath := fmt.Sprintf("/api/v4/projects/%d/repository/commits?ref_name=%s&per_page=1",
+		project.ID, url.QueryEscape(project.DefaultBranch))
+	if err := f.get(commitsPath, &commits); err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	commit := commits[0]
+
+	entry := latestProjectEntry{
+		RepoName:        project.Name,
+		RepoUrl:         project.WebURL,
+		RepoLang:        f.primaryLanguage(project.ID),
+		BranchName:      project.DefaultBranch,
+		BranchUrl:       project.WebURL + "/-/tree/" + project.DefaultBranch,
+		CommitID:        commit.ShortID,
+		CommitUrl:       commit.WebURL,
+		CommitAuthorID:  commit.AuthorName,
+		CommitAuthorUrl: "",
+	}
+	entry.Time = fmtDuration(baseTime.Sub(project.LastActivityAt).Round(time.Minute))
+	if entry.RepoLang == "" {
+		entry.RepoLang = "unknown"
+	}
+	return &entry, nil
+}
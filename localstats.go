@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	git "github.com/gogs/git-module"
+)
+
+// mirrorCacheDir holds local mirror clones used to compute diff stats that
+// the GraphQL/REST APIs don't expose cheaply.
+const mirrorCacheDir = ".git-mirrors"
+
+// enrichWithLocalStats populates LinesAdded, LinesDeleted, FilesChanged and
+// DiffSummary on entries by shelling out to git against a local mirror
+// clone of each repo, the way Gitea uses git-module for local repo
+// introspection. This is opt-in (--git-stats) since it clones every repo
+// in the listing.
+func enrichWithLocalStats(entries []latestProjectEntry) error {
+	if err := os.MkdirAll(mirrorCacheDir, 0755); err != nil {
+		return fmt.Errorf("git-stats: %w", err)
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		dir := filepath.Join(mirrorCacheDir, mirrorDirName(entry.RepoUrl))
+
+		if err := mirrorClone(entry.RepoUrl, dir); err != nil {
+			return fmt.Errorf("git-stats: mirroring %s: %w", entry.RepoUrl, err)
+		}
+
+		repo, err := git.Open(dir)
+		if err != nil {
+			return fmt.Errorf("git-stats: opening %s: %w", dir, err)
+		}
+
+		diff, err := repo.Diff(entry.CommitID, 0, 0, 0)
+		if err != nil {
+			return fmt.Errorf("git-stats: diffing %s@%s: %w", entry.RepoUrl, entry.CommitID, err)
+		}
+
+		for _, f := range diff.Files {
+			entry.LinesAdded += f.NumAdditions()
+			entry.LinesDeleted += f.NumDeletions()
+		}
+		entry.FilesChanged = len(diff.Files)
+		entry.DiffSummary = fmt.Sprintf("%d files changed, +%d -%d", entry.FilesChanged, entry.LinesAdded, entry.LinesDeleted)
+	}
+
+	return nil
+}
+
+// mirrorDirName turns a repo URL into a filesystem-safe, collision-free
+// mirror directory name, so two same-named repos under different
+// accounts (e.g. via GITHUB_EXTRA_SOURCES) never share one mirror.
+func mirrorDirName(repoUrl string) string {
+	name := strings.TrimPrefix(repoUrl, "https://")
+	name = strings.TrimPrefix(name, "http://")
+	return strings.NewReplacer("/", "_", ":", "_").Replace(name)
+}
+
+// mirrorClone clones url into dir as a bare mirror on first use, and
+// fetches into the existing mirror on subsequent runs.
+func mirrorClone(url, dir string) error {
+	if _, err := os.Stat(dir); err == nil {
+		repo, err := git.Open(dir)
+		if err != nil {
+			return err
+		}
+		return repo.Fetch()
+	}
+	return git.Clone(url, dir, git.CloneOptions{Mirror: true})
+}
@@ -2,174 +2,24 @@ package main
 
 import (
 	"bytes"
-	"context"
+	"flag"
 	"fmt"
-	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 	"html/template"
 	"os"
-	"sort"
-	"time"
 )
 
-/*
-GraphQL:
-
-{
-  viewer {
-	login
-    repositories(first: 100, privacy: PUBLIC, orderBy: {field: PUSHED_AT, direction: DESC}) {
-      nodes {
-        name
-        url
-        primaryLanguage {
-          name
-        }
-        pushedAt
-        isFork
-        refs(refPrefix: "refs/heads/", orderBy: {field: TAG_COMMIT_DATE, direction: DESC}, first: 1) {
-          edges {
-            node {
-              name
-              target {
-                ... on Commit {
-                  history(first: 1) {
-                    edges {
-                      node {
-                        commitUrl
-                        author {
-                          user {
-                            login
-                            url
-                          }
-                        }
-                      }
-                    }
-                  }
-                }
-              }
-            }
-          }
-        }
-      }
-    }
-  }
-}
-*/
+const readmeFile = "README.md"
 
 const (
-	latestRepoCnt    = 5
-	enableSortByName = true
-	originReadmeFile = "./README-1.md"
+	latestProjectsStart = "<!-- LATEST_PROJECTS:START -->"
+	latestProjectsEnd   = "<!-- LATEST_PROJECTS:END -->"
 )
 
-type latestProjectEntry struct {
-	// repo info
-	RepoName string
-	RepoUrl  string
-	RepoLang string
-
-	// commit info
-	BranchName      string
-	BranchUrl       string
-	CommitID        string
-	CommitUrl       string
-	CommitAuthorID  string
-	CommitAuthorUrl string
-
-	// time info
-	Time string
-}
-
-func fmtDuration(d time.Duration) string {
-	h := d / time.Hour
-	d -= h * time.Hour
-	m := d / time.Minute
-	return fmt.Sprintf("%d hours %d minutes", h, m)
-}
-
-func fetchLatestProjects(client *githubv4.Client) []latestProjectEntry {
-	variables := map[string]interface{}{
-		"latestRepoCnt": githubv4.Int(latestRepoCnt),
-	}
-	// get latest pushed repo
-	var query struct {
-		Viewer struct {
-			Login        string
-			Repositories struct {
-				Nodes []struct {
-					Name            string
-					Description     string
-					Url             string
-					PrimaryLanguage struct {
-						Name string
-					}
-					PushedAt time.Time
-					IsFork   bool
-					Refs     struct {
-						Edges []struct {
-							Node struct {
-								Name   string
-								Target struct {
-									Commit struct {
-										History struct {
-											Edges []struct {
-												Node struct {
-													CommitUrl      string
-													AbbreviatedOid string
-													Author         struct {
-														User struct {
-															Login string
-															Url   string
-														}
-													}
-												}
-											}
-										} `graphql:"history(first: 1)"`
-									} `graphql:"... on Commit"`
-								}
-							}
-						}
-					} `graphql:"refs(refPrefix: \"refs/heads/\", orderBy: {field: TAG_COMMIT_DATE, direction: DESC}, first: 1)"`
-				}
-			} `graphql:"repositories(first: $latestRepoCnt, privacy: PUBLIC, orderBy: {field: PUSHED_AT, direction: DESC})"`
-		}
-	}
-	err := client.Query(context.Background(), &query, variables)
-	if err != nil {
-		panic(err)
-	}
-
-	// parse result
-	var result []latestProjectEntry
-	baseTime := time.Now()
-	for _, repo := range query.Viewer.Repositories.Nodes {
-		entry := latestProjectEntry{
-			RepoName:        repo.Name,
-			RepoUrl:         repo.Url,
-			RepoLang:        repo.PrimaryLanguage.Name,
-			BranchName:      repo.Refs.Edges[0].Node.Name,
-			BranchUrl:       repo.Url + "/tree/" + repo.Refs.Edges[0].Node.Name,
-			CommitUrl:       repo.Refs.Edges[0].Node.Target.Commit.History.Edges[0].Node.CommitUrl,
-			CommitID:        repo.Refs.Edges[0].Node.Target.Commit.History.Edges[0].Node.AbbreviatedOid,
-			CommitAuthorID:  repo.Refs.Edges[0].Node.Target.Commit.History.Edges[0].Node.Author.User.Login,
-			CommitAuthorUrl: repo.Refs.Edges[0].Node.Target.Commit.History.Edges[0].Node.Author.User.Url,
-		}
-		durationTime := baseTime.Sub(repo.PushedAt).Round(time.Minute)
-		entry.Time = fmtDuration(durationTime)
-		if entry.RepoLang == "" {
-			entry.RepoLang = "unknown"
-		}
-		result = append(result, entry)
-	}
-	if enableSortByName {
-		sort.SliceStable(result, func(i, j int) bool {
-			return result[i].CommitAuthorID == query.Viewer.Login && result[j].CommitAuthorID != query.Viewer.Login
-		})
-	}
-
-	return result
-}
+var sourceFlag = flag.String("source", "github", "git hosting backend to fetch from: github, gitlab, or gitea")
+var modeFlag = flag.String("mode", "", `extra README section to generate instead of the latest-projects listing, e.g. "stats"`)
+var dryRunFlag = flag.Bool("dry-run", false, "print the README diff instead of writing it")
+var checkFlag = flag.Bool("check", false, "exit non-zero if README.md would change, without writing it")
+var gitStatsFlag = flag.Bool("git-stats", false, "shell out to a local git mirror clone of each repo to add a diff-stats column (slow: clones every repo)")
 
 var markdownTmpl = `
 - [{{.RepoName}}]({{.RepoUrl}}) on branch [{{.BranchName}}]({{.BranchUrl}}) with commit [{{.CommitID}}]({{.CommitUrl}}) by [@{{.CommitAuthorID}}]({{.CommitAuthorUrl}}) {{.Time}} ago  ![](https://img.shields.io/badge/language-{{.RepoLang}}-default.svg?style=flat-square)`
@@ -182,20 +32,55 @@ var markdownTableHeaderTmpl = `
 |:---:|:---:|:---:|:---:|:---:|:---:|
 `
 
+// markdownTableWithStatsTmpl/markdownTableHeaderWithStatsTmpl add the diff
+// column populated by --git-stats.
+var markdownTableWithStatsTmpl = `| [{{.RepoName}}]({{.RepoUrl}}) | [{{.BranchName}}]({{.BranchUrl}}) |[{{.CommitID}}]({{.CommitUrl}}) | [@{{.CommitAuthorID}}]({{.CommitAuthorUrl}}) |{{.Time}} | ![](https://img.shields.io/badge/language-{{.RepoLang}}-default.svg?style=flat-square)|{{.DiffSummary}}|
+`
+
+var markdownTableHeaderWithStatsTmpl = `
+| repo | branch | commit | author | time since | language | diff |
+|:---:|:---:|:---:|:---:|:---:|:---:|:---:|
+`
+
 func main() {
-	// authenticate to github
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	client := githubv4.NewClient(httpClient)
-	r := fetchLatestProjects(client)
+	flag.Parse()
+
+	if *modeFlag != "" {
+		m, ok := modes[*modeFlag]
+		if !ok {
+			panic(fmt.Sprintf("unknown --mode %q", *modeFlag))
+		}
+		block, err := m.handler()
+		if err != nil {
+			panic(err)
+		}
+		applyReadmeUpdate(readmeFile, m.start, m.end, block)
+		return
+	}
+
+	fetcher, err := newFetcher(*sourceFlag)
+	if err != nil {
+		panic(err)
+	}
+	r, err := fetcher.FetchLatestProjects()
+	if err != nil {
+		panic(err)
+	}
+
+	tableTmpl, headerTmpl := markdownTableTmpl, markdownTableHeaderTmpl
+	if *gitStatsFlag {
+		if err := enrichWithLocalStats(r); err != nil {
+			panic(err)
+		}
+		tableTmpl, headerTmpl = markdownTableWithStatsTmpl, markdownTableHeaderWithStatsTmpl
+	}
 
 	// generate template
 	buf := new(bytes.Buffer)
+	buf.WriteString(headerTmpl)
 	for _, v := range r {
 		t := template.New("markdown")
-		t, err := t.Parse(markdownTableTmpl)
+		t, err := t.Parse(tableTmpl)
 		if err != nil {
 			panic(err)
 		}
@@ -205,10 +90,31 @@ func main() {
 		}
 	}
 
-	// append to README-1.md && rename to README.md
-	f, _ := os.OpenFile(originReadmeFile, os.O_WRONLY|os.O_APPEND, 0755)
-	defer f.Close()
-	_, _ = f.WriteString(markdownTableHeaderTmpl)
-	_, _ = f.Write(buf.Bytes())
-	_ = os.Rename(originReadmeFile, "README.md")
+	applyReadmeUpdate(readmeFile, latestProjectsStart, latestProjectsEnd, buf.String())
+}
+
+// applyReadmeUpdate rewrites the marked section of path with body according
+// to --dry-run/--check: by default it writes the file in place, --dry-run
+// prints the diff instead, and --check exits 1 without writing if the file
+// would change (for use in CI).
+func applyReadmeUpdate(path, start, end, body string) {
+	oldContent, newContent, err := prepareReadmeUpdate(path, start, end, body)
+	if err != nil {
+		panic(err)
+	}
+	if oldContent == newContent {
+		return
+	}
+
+	if *dryRunFlag {
+		writeLineDiff(os.Stdout, oldContent, newContent)
+		return
+	}
+	if *checkFlag {
+		fmt.Fprintf(os.Stderr, "%s would change; run without --check to update it\n", path)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,18 @@
+package main
+
+// mode is an optional README section generator selected with --mode. Unlike
+// the default latest-pushed-projects listing, each mode renders into its own
+// HTML-comment-delimited block so the updater can replace it idempotently on
+// re-runs, instead of blindly appending.
+type mode struct {
+	start, end string
+	handler    func() (string, error)
+}
+
+var modes = map[string]mode{
+	"stats": {
+		start:   "<!-- STATS:START -->",
+		end:     "<!-- STATS:END -->",
+		handler: statsBlock,
+	},
+}
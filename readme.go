@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// replaceMarkedSection rewrites the region of content between start and end
+// (markers included) with body, so re-running a mode never duplicates its
+// section. If the markers aren't present yet, the section is appended.
+func replaceMarkedSection(content, start, end, body string) string {
+	section := start + "\n" + body + "\n" + end
+
+	startIdx := strings.Index(content, start)
+	endIdx := strings.Index(content, end)
+	if startIdx == -1 || endIdx == -1 || endIdx < startIdx {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + section + "\n"
+	}
+
+	return content[:startIdx] + section + content[endIdx+len(end):]
+}
+
+// prepareReadmeUpdate reads path and computes what it would look like with
+// its marked section replaced by body, without writing anything.
+func prepareReadmeUpdate(path, start, end, body string) (oldContent, newContent string, err error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	oldContent = string(existing)
+	newContent = replaceMarkedSection(oldContent, start, end, body)
+	return oldContent, newContent, nil
+}
+
+// writeLineDiff prints a minimal line-based diff of old vs newContent,
+// eliding the lines they share as a common prefix/suffix.
+func writeLineDiff(w io.Writer, old, newContent string) {
+	oldLines := strings.Split(old, "\n")
+	newLines := strings.Split(newContent, "\n")
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	for _, l := range oldLines[prefix : len(oldLines)-suffix] {
+		fmt.Fprintf(w, "-%s\n", l)
+	}
+	for _, l := range newLines[prefix : len(newLines)-suffix] {
+		fmt.Fprintf(w, "+%s\n", l)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxGraphQLRetries bounds how many times retryingTransport will resend a
+// request that hit a transient 5xx or secondary rate limit.
+const maxGraphQLRetries = 5
+
+// retryingTransport wraps an http.RoundTripper with exponential-backoff
+// retries, the same pattern maintner-style tools use when doing long GraphQL
+// walks: retry on 5xx and secondary-rate-limit responses, and honor
+// GitHub's Retry-After/X-RateLimit-Remaining headers between requests.
+type retryingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= maxGraphQLRetries; attempt++ {
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err = base.RoundTrip(req)
+		if err != nil || !shouldRetry(resp) || attempt == maxGraphQLRetries {
+			return resp, err
+		}
+
+		wait := retryDelay(attempt, resp)
+		resp.Body.Close()
+		time.Sleep(wait)
+	}
+	return resp, err
+}
+
+// shouldRetry reports whether resp looks like a transient failure worth
+// retrying: a server error, or GitHub signalling a rate limit via
+// Retry-After or a spent X-RateLimit-Remaining budget.
+func shouldRetry(resp *http.Response) bool {
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	if resp.Header.Get("Retry-After") != "" {
+		return true
+	}
+	return resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// retryDelay honors Retry-After when GitHub sends one, falling back to
+// exponential backoff from 1s.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return time.Duration(1<<uint(attempt)) * time.Second
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"text/template"
+	"time"
+)
+
+// statsWindowDays is how far back the contribution summary looks.
+const statsWindowDays = 90
+
+var statsTableTmpl = `
+| metric | count |
+|:---:|:---:|
+| commits | {{.Commits}} |
+| issues opened | {{.IssuesOpened}} |
+| pull requests opened | {{.PullRequestsOpened}} |
+| pull request reviews | {{.Reviews}} |
+`
+
+type statsSummary struct {
+	Commits            int
+	IssuesOpened       int
+	PullRequestsOpened int
+	Reviews            int
+}
+
+// statsBlock queries the viewer's GraphQL contribution calendar for the
+// trailing statsWindowDays and renders it as a markdown table, in the spirit
+// of golang.org/x/build/cmd/gopherstats' contribution summaries.
+func statsBlock() (string, error) {
+	client := newGitHubClient()
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -statsWindowDays)
+
+	var query struct {
+		Viewer struct {
+			ContributionsCollection struct {
+				TotalCommitContributions            int
+				TotalIssueContributions             int
+				TotalPullRequestContributions       int
+				TotalPullRequestReviewContributions int
+			} `graphql:"contributionsCollection(from: $from, to: $to)"`
+		}
+	}
+	variables := map[string]interface{}{
+		"from": githubv4.DateTime{Time: from},
+		"to":   githubv4.DateTime{Time: to},
+	}
+	if err := client.Query(context.Background(), &query, variables); err != nil {
+		return "", fmt.Errorf("stats: %w", err)
+	}
+
+	summary := statsSummary{
+		Commits:            query.Viewer.ContributionsCollection.TotalCommitContributions,
+		IssuesOpened:       query.Viewer.ContributionsCollection.TotalIssueContributions,
+		PullRequestsOpened: query.Viewer.ContributionsCollection.TotalPullRequestContributions,
+		Reviews:            query.Viewer.ContributionsCollection.TotalPullRequestReviewContributions,
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("### Contribution stats (last %d days)\n", statsWindowDays))
+	t := template.Must(template.New("stats").Parse(statsTableTmpl))
+	if err := t.Execute(buf, summary); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}